@@ -0,0 +1,145 @@
+package fpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// BeaconSource produces successive verifiable-random-beacon rounds, e.g. a
+// drand chained-mode client.
+type BeaconSource interface {
+	Next(ctx context.Context) (index uint64, random float64, proof []byte, err error)
+}
+
+// BeaconVerifier verifies a beacon round produced by a BeaconSource, so
+// Instance.RunWithBeacon never ticks on a random it can't attribute to the
+// beacon committee.
+type BeaconVerifier interface {
+	Verify(index uint64, random float64, proof []byte) error
+}
+
+// RandomFromSignature derives FPC's random value in [0,1] from a beacon
+// signature, as H(sig) interpreted as a big-endian uint64 over math.MaxUint64.
+func RandomFromSignature(sig []byte) float64 {
+	h := sha256.Sum256(sig)
+	return float64(binary.BigEndian.Uint64(h[:8])) / math.MaxUint64
+}
+
+// VerifySignature checks an aggregated BLS signature over message against the
+// committee's distributed public key.
+type VerifySignature func(pubKey, message, sig []byte) error
+
+// EncodeProof packs a chained-mode drand round into the flat byte slice
+// carried by BeaconSource.Next / BeaconVerifier.Verify: the previous round's
+// signature (length-prefixed) followed by this round's aggregate signature.
+// Carrying prevSig alongside sig - exactly as drand's own chained-mode
+// responses do - lets DrandVerifier check any round on its own merits,
+// instead of depending on having successfully verified its predecessor.
+func EncodeProof(prevSig, sig []byte) []byte {
+	buf := make([]byte, 4+len(prevSig)+len(sig))
+	binary.BigEndian.PutUint32(buf, uint32(len(prevSig)))
+	copy(buf[4:], prevSig)
+	copy(buf[4+len(prevSig):], sig)
+	return buf
+}
+
+// decodeProof is the inverse of EncodeProof.
+func decodeProof(proof []byte) (prevSig, sig []byte, err error) {
+	if len(proof) < 4 {
+		return nil, nil, errors.New("beacon proof too short")
+	}
+	prevSigLen := binary.BigEndian.Uint32(proof)
+	if uint32(len(proof)) < 4+prevSigLen {
+		return nil, nil, errors.New("beacon proof truncated")
+	}
+	return proof[4 : 4+prevSigLen], proof[4+prevSigLen:], nil
+}
+
+// DrandVerifier is a BeaconVerifier for beacons compatible with drand's
+// chained mode: a round's message is H(prevSig || index), and its signature
+// is verified as the committee's aggregated signature over that message.
+// Because proof embeds its own prevSig (see EncodeProof), each round
+// verifies independently of whether prior rounds were seen or verified -
+// a missed or failed round never wedges verification of later ones, so a
+// node can resync after packet loss or a beacon jump without a special case.
+type DrandVerifier struct {
+	pubKey []byte
+	verify VerifySignature
+}
+
+// NewDrandVerifier returns a DrandVerifier that checks proofs against pubKey,
+// using verify to check the underlying BLS aggregate signature.
+func NewDrandVerifier(pubKey []byte, verify VerifySignature) *DrandVerifier {
+	return &DrandVerifier{pubKey: pubKey, verify: verify}
+}
+
+// Verify checks that proof decodes to a valid aggregated signature over
+// H(prevSig || index) under the committee's distributed public key, and that
+// random was correctly derived from it.
+func (v *DrandVerifier) Verify(index uint64, random float64, proof []byte) error {
+	prevSig, sig, err := decodeProof(proof)
+	if err != nil {
+		return fmt.Errorf("beacon round %d: %w", index, err)
+	}
+	if err := v.verify(v.pubKey, beaconMessage(prevSig, index), sig); err != nil {
+		return fmt.Errorf("beacon round %d: %w", index, err)
+	}
+	if RandomFromSignature(sig) != random {
+		return fmt.Errorf("beacon round %d: random does not match signature", index)
+	}
+	return nil
+}
+
+// beaconMessage builds the round message H(prevSig || index) signed by the
+// drand committee for a chained-mode beacon.
+func beaconMessage(prevSig []byte, index uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, index)
+	h := sha256.Sum256(append(append([]byte{}, prevSig...), buf...))
+	return h[:]
+}
+
+// RunWithBeacon pulls successive rounds from source, verifies each with
+// verifier, and feeds verified (index, random) pairs into the same round
+// logic as Tick. Rounds that fail verification are rejected and held: the
+// loop simply waits for the next beacon round instead of ticking on it. If
+// the beacon jumps ahead, RunWithBeacon follows it rather than replaying the
+// skipped indices, so a colluding tick source can't force a stale x onto this
+// node. The old Tick is kept for tests that don't need beacon verification.
+// RunWithBeacon blocks until ctx is canceled or source.Next returns an error.
+func (fpc *Instance) RunWithBeacon(ctx context.Context, source BeaconSource, verifier BeaconVerifier) error {
+	var lastIndex uint64
+	seenFirst := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		index, random, proof, err := source.Next(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := verifier.Verify(index, random, proof); err != nil {
+			continue
+		}
+
+		if seenFirst && index <= lastIndex {
+			continue
+		}
+		seenFirst = true
+		lastIndex = index
+
+		fpc.stateMu.Lock()
+		fpc.state.tick = newTick(index, random)
+		fpc.stateMu.Unlock()
+		fpc.finalizedTxsChannel <- fpc.round()
+	}
+}
@@ -0,0 +1,124 @@
+package fpc
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEncodeDecodeProofRoundTrips(t *testing.T) {
+	prevSig := []byte("prev-signature")
+	sig := []byte("this-round-signature")
+
+	proof := EncodeProof(prevSig, sig)
+
+	gotPrevSig, gotSig, err := decodeProof(proof)
+	if err != nil {
+		t.Fatalf("decodeProof: %v", err)
+	}
+	if !bytes.Equal(gotPrevSig, prevSig) {
+		t.Fatalf("expected prevSig %q, got %q", prevSig, gotPrevSig)
+	}
+	if !bytes.Equal(gotSig, sig) {
+		t.Fatalf("expected sig %q, got %q", sig, gotSig)
+	}
+}
+
+func TestEncodeDecodeProofRoundTripsEmptyPrevSig(t *testing.T) {
+	// genesis round: no previous signature yet
+	proof := EncodeProof(nil, []byte("sig"))
+
+	prevSig, sig, err := decodeProof(proof)
+	if err != nil {
+		t.Fatalf("decodeProof: %v", err)
+	}
+	if len(prevSig) != 0 {
+		t.Fatalf("expected empty prevSig, got %q", prevSig)
+	}
+	if !bytes.Equal(sig, []byte("sig")) {
+		t.Fatalf("expected sig %q, got %q", "sig", sig)
+	}
+}
+
+func TestDecodeProofRejectsTruncated(t *testing.T) {
+	if _, _, err := decodeProof([]byte{0, 0}); err == nil {
+		t.Fatalf("expected an error for a proof shorter than the length prefix")
+	}
+	truncated := EncodeProof([]byte("prev"), []byte("sig"))[:6]
+	if _, _, err := decodeProof(truncated); err == nil {
+		t.Fatalf("expected an error for a proof truncated before its declared prevSig")
+	}
+}
+
+func TestDrandVerifierVerifySuccess(t *testing.T) {
+	pubKey := []byte("pubkey")
+	prevSig := []byte("prev-sig")
+	sig := []byte("sig")
+
+	verify := func(gotPubKey, message, gotSig []byte) error {
+		if !bytes.Equal(gotPubKey, pubKey) {
+			t.Fatalf("unexpected pubkey passed to verify")
+		}
+		if !bytes.Equal(gotSig, sig) {
+			t.Fatalf("unexpected sig passed to verify")
+		}
+		if !bytes.Equal(message, beaconMessage(prevSig, 7)) {
+			t.Fatalf("unexpected message passed to verify")
+		}
+		return nil
+	}
+
+	v := NewDrandVerifier(pubKey, verify)
+	proof := EncodeProof(prevSig, sig)
+	random := RandomFromSignature(sig)
+
+	if err := v.Verify(7, random, proof); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+func TestDrandVerifierVerifyRejectsBadSignature(t *testing.T) {
+	v := NewDrandVerifier([]byte("pubkey"), func(pubKey, message, sig []byte) error {
+		return errors.New("signature does not verify")
+	})
+
+	proof := EncodeProof([]byte("prev-sig"), []byte("sig"))
+	if err := v.Verify(1, RandomFromSignature([]byte("sig")), proof); err == nil {
+		t.Fatalf("expected an error when the underlying signature check fails")
+	}
+}
+
+func TestDrandVerifierVerifyRejectsMismatchedRandom(t *testing.T) {
+	v := NewDrandVerifier([]byte("pubkey"), func(pubKey, message, sig []byte) error {
+		return nil
+	})
+
+	proof := EncodeProof([]byte("prev-sig"), []byte("sig"))
+	if err := v.Verify(1, 0.5, proof); err == nil {
+		t.Fatalf("expected an error when random doesn't match the signature")
+	}
+}
+
+// TestDrandVerifierVerifyMissedRoundDoesNotWedgeLaterRounds exercises the
+// stateless, proof-embeds-prevSig design: verifying a round never depends on
+// whether a previous round was seen or successfully verified.
+func TestDrandVerifierVerifyMissedRoundDoesNotWedgeLaterRounds(t *testing.T) {
+	v := NewDrandVerifier([]byte("pubkey"), func(pubKey, message, sig []byte) error {
+		if bytes.Equal(sig, []byte("bad-sig")) {
+			return errors.New("signature does not verify")
+		}
+		return nil
+	})
+
+	badProof := EncodeProof([]byte("sig-at-round-1"), []byte("bad-sig"))
+	if err := v.Verify(2, RandomFromSignature([]byte("bad-sig")), badProof); err == nil {
+		t.Fatalf("expected round 2 to fail verification")
+	}
+
+	// round 3's proof carries its own prevSig (round 2's signature), and
+	// verifies independently of round 2 ever having succeeded
+	goodProof := EncodeProof([]byte("sig-at-round-2"), []byte("sig-at-round-3"))
+	if err := v.Verify(3, RandomFromSignature([]byte("sig-at-round-3")), goodProof); err != nil {
+		t.Fatalf("expected round 3 to verify despite round 2 having failed: %v", err)
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
-	"time"
+	"sync"
 )
 
 type Voter interface {
@@ -29,23 +29,85 @@ type GetKnownPeers func() (nodeIDs []string)
 // QueryNode defines the signature function
 type QueryNode func(txs []ID, nodeID string) Opinions
 
+// GossipOpinion pushes the given node's current interim opinion of txs to the given peers.
+type GossipOpinion func(txs []TxOpinion, nodeIDs []string) error
+
+// GetPeerWeights returns the current mana-derived weight of every known peer,
+// keyed by nodeID. Peers absent from the map are treated as having zero weight.
+type GetPeerWeights func() map[string]float64
+
+// OpinionRecorder is notified at the end of every round with that round's
+// state, for persistence and post-mortem analysis.
+type OpinionRecorder interface {
+	RecordRound(index uint64, random float64, activeTxs map[ID]EtaSnapshot, opinions map[ID]Opinions)
+}
+
 // Instance defines an FPC object
 type Instance struct {
 	Fpc
 	getKnownPeers       GetKnownPeers
 	queryNode           QueryNode
+	gossipOpinion       GossipOpinion
+	getPeerWeights      GetPeerWeights
 	state               *context
 	finalizedTxsChannel chan []TxOpinion
+	gossip              *gossipState
+	rng                 *rand.Rand
+	rngMu               sync.Mutex
+	recorder            OpinionRecorder
+	// stateMu guards state.activeTxs and state.tick, which the Tick/
+	// RunWithBeacon goroutine mutates and which GossipTick's gossipRound and
+	// ReceiveGossip also read from a second goroutine.
+	stateMu sync.Mutex
 }
 
 // New returns a new FPC instance
-func New(gkp GetKnownPeers, qn QueryNode, parameters *Parameters) *Instance {
+func New(gkp GetKnownPeers, qn QueryNode, gpw GetPeerWeights, parameters *Parameters) *Instance {
 	return &Instance{
 		state:               newContext(parameters),
 		getKnownPeers:       gkp,
 		queryNode:           qn,
+		getPeerWeights:      gpw,
 		finalizedTxsChannel: make(chan []TxOpinion),
+		gossip:              newGossipState(),
+		rng:                 rand.New(rand.NewSource(cryptoRandSeed())),
+	}
+}
+
+// WithGossip enables the push-style gossip dissemination of interim opinions,
+// used to speed up convergence for nodes that lost queries.
+func (fpc *Instance) WithGossip(gossipOpinion GossipOpinion) *Instance {
+	fpc.gossipOpinion = gossipOpinion
+	return fpc
+}
+
+// WithRecorder enables recording of every round's state through recorder,
+// for post-mortem analysis or replay via ReplayFromRecords.
+func (fpc *Instance) WithRecorder(recorder OpinionRecorder) *Instance {
+	fpc.recorder = recorder
+	return fpc
+}
+
+// ReplayFromRecords rebuilds an Instance's opinionHistory/activeTxs from the
+// given records, so a crashed node can resume mid-vote instead of losing its
+// in-flight opinions. Only the last record is used to seed state; earlier
+// records are assumed to already be reflected in it.
+func ReplayFromRecords(gkp GetKnownPeers, qn QueryNode, gpw GetPeerWeights, parameters *Parameters, records []RoundRecord) *Instance {
+	fpc := New(gkp, qn, gpw, parameters)
+	if len(records) == 0 {
+		return fpc
 	}
+
+	last := records[len(records)-1]
+	for tx, eta := range last.ActiveTxs {
+		fpc.state.activeTxs[tx] = &etaResult{value: eta.Value, count: eta.Count}
+	}
+	for tx, opinions := range last.Opinions {
+		for _, opinion := range opinions {
+			fpc.state.opinionHistory.Store(tx, opinion)
+		}
+	}
+	return fpc
 }
 
 // SubmitTxsForVoting adds given txs to the FPC internal state
@@ -61,7 +123,9 @@ func (fpc *Instance) FinalizedTxsChannel() <-chan []TxOpinion {
 // Tick updates fpc state with the new random
 // and starts a new round
 func (fpc *Instance) Tick(index uint64, random float64) {
+	fpc.stateMu.Lock()
 	fpc.state.tick = newTick(index, random)
+	fpc.stateMu.Unlock()
 	go func() { fpc.finalizedTxsChannel <- fpc.round() }()
 }
 
@@ -109,9 +173,27 @@ func newEtaMap() etaMap {
 // EtaResult defines the eta of an FPC round of a tx
 // Value is the value of eta
 // Count is how many nodes replied to our query
+// Weight is the combined mana weight of the nodes that replied
 type etaResult struct {
-	value float64
-	count int
+	value  float64
+	count  int
+	weight float64
+}
+
+// EtaSnapshot is a serializable snapshot of a round's eta for a single tx,
+// exported for use by OpinionRecorder implementations outside this package.
+type EtaSnapshot struct {
+	Value float64
+	Count int
+}
+
+// RoundRecord is a single round's recorded state, as produced by
+// OpinionRecorder implementations and consumed by ReplayFromRecords.
+type RoundRecord struct {
+	Index     uint64
+	Random    float64
+	ActiveTxs map[ID]EtaSnapshot
+	Opinions  map[ID]Opinions
 }
 
 type tick struct {
@@ -130,6 +212,12 @@ func newTick(index uint64, random float64) *tick {
 // i: fpc param
 // o: list of finalized txs (if any)
 func (fpc *Instance) round() []TxOpinion {
+	// stateMu is held for the whole round: GossipTick's gossipRound and
+	// ReceiveGossip read the same activeTxs/tick state from a second
+	// goroutine and must not observe it mid-update.
+	fpc.stateMu.Lock()
+	defer fpc.stateMu.Unlock()
+
 	// pop new txs from waiting list and put them into the active list
 	fpc.state.popTxs()
 
@@ -138,14 +226,48 @@ func (fpc *Instance) round() []TxOpinion {
 	finalized := fpc.getFinalizedTxs()
 
 	// send the query for all the txs
-	etas := querySample(fpc.state.getActiveTxs(), fpc.state.parameters.k, fpc.getKnownPeers(), fpc.queryNode)
-	for tx, eta := range etas {
-		fpc.state.activeTxs[tx] = eta
+	// if the selected sample doesn't reach quorum weight, the round is held:
+	// the previous etas stay in place instead of being replaced by
+	// under-sampled ones, and the query is retried next tick
+	etas, ok := fpc.querySample(fpc.state.getActiveTxs())
+	if ok {
+		if fpc.gossipOpinion != nil {
+			etas = fpc.gossip.fuse(etas, fpc.state.parameters.alpha)
+		}
+		for tx, eta := range etas {
+			fpc.state.activeTxs[tx] = eta
+		}
+	}
+
+	if fpc.recorder != nil {
+		fpc.recorder.RecordRound(fpc.state.tick.index, fpc.state.tick.x, fpc.snapshotActiveTxs(), fpc.snapshotOpinions())
 	}
 
 	return finalized
 }
 
+// snapshotActiveTxs returns an exported, serializable snapshot of the current
+// active txs' etas, for OpinionRecorder.
+func (fpc *Instance) snapshotActiveTxs() map[ID]EtaSnapshot {
+	snapshot := make(map[ID]EtaSnapshot, len(fpc.state.activeTxs))
+	for tx, eta := range fpc.state.activeTxs {
+		snapshot[tx] = EtaSnapshot{Value: eta.value, Count: eta.count}
+	}
+	return snapshot
+}
+
+// snapshotOpinions returns the full opinion history of every active tx, for
+// OpinionRecorder.
+func (fpc *Instance) snapshotOpinions() map[ID]Opinions {
+	snapshot := make(map[ID]Opinions, len(fpc.state.activeTxs))
+	for tx := range fpc.state.activeTxs {
+		if history, ok := fpc.state.opinionHistory.Load(tx); ok {
+			snapshot[tx] = history
+		}
+	}
+	return snapshot
+}
+
 // returns the last opinion
 // i: list of opinions stored during FPC rounds of a particular tx
 func getLastOpinion(list Opinions) (bool, error) {
@@ -192,6 +314,7 @@ func (fpc *Instance) getFinalizedTxs() []TxOpinion {
 			finalized = append(finalized, TxOpinion{tx, lastOpinion})
 			fpc.state.opinionHistory.Delete(tx)
 			delete(fpc.state.activeTxs, tx)
+			fpc.gossip.forget(tx)
 		}
 	}
 	return finalized
@@ -215,65 +338,79 @@ func isFinal(o Opinions, m, l int) bool {
 	return true
 }
 
-// querySample sends query to randomly selected nodes
-func querySample(txs []ID, k int, nodes []string, qn QueryNode) etaMap {
-	// select k random nodes
-	selectedNodes := choose(nodes, k)
+// querySample sends a query to a mana-weighted sample of nodes and returns
+// the resulting, weight-normalized etas. ok is false if the selected sample's
+// combined weight doesn't reach Parameters.MinTotalWeight, in which case the
+// caller should hold the round rather than trust the (under-sampled) etas.
+func (fpc *Instance) querySample(txs []ID) (etas etaMap, ok bool) {
+	nodes := fpc.getKnownPeers()
+	weights := fpc.getPeerWeights()
+	k := fpc.state.parameters.k
+
+	// select k nodes weighted by mana, without replacement
+	selectedNodes, totalWeight := fpc.chooseWeighted(nodes, weights, k)
+	if totalWeight < fpc.state.parameters.MinTotalWeight {
+		return nil, false
+	}
 
 	// send k queries
-	c := make(chan Opinions, k) // channel to communicate the reception of all the responses
+	c := make(chan weightedOpinions, k) // channel to communicate the reception of all the responses
 	for _, node := range selectedNodes {
 		go func(nodeID string) {
-			received := qn(txs, nodeID)
-			c <- received
-			//fmt.Println("Asked:", txs, "Received:",  received)
+			received := fpc.queryNode(txs, nodeID)
+			c <- weightedOpinions{opinions: received, weight: weights[nodeID]}
 		}(node)
 	}
 
 	// wait for all the responses and merge them
-	result := []TxOpinion{}
-	for i := 0; i < k; i++ {
+	result := []weightedVote{}
+	for i := 0; i < len(selectedNodes); i++ {
 		votes := <-c
-		if len(votes) > 0 {
-			for voteIdx, vote := range votes {
-				result = append(result, TxOpinion{txs[voteIdx], vote})
+		if len(votes.opinions) > 0 {
+			for voteIdx, vote := range votes.opinions {
+				result = append(result, weightedVote{TxOpinion{txs[voteIdx], vote}, votes.weight})
 			}
 		}
 	}
 
-	return calculateEtas(result)
+	return calculateEtas(result), true
+}
+
+// weightedOpinions pairs a query response with the weight of the node
+// that produced it.
+type weightedOpinions struct {
+	opinions Opinions
+	weight   float64
+}
+
+// weightedVote pairs a single vote with the weight of the node that cast it.
+type weightedVote struct {
+	TxOpinion
+	weight float64
 }
 
-// process the responses by calclulating etas
-// for all the votes
-func calculateEtas(votes []TxOpinion) etaMap {
+// process the responses by calculating weight-normalized etas for all the votes
+func calculateEtas(votes []weightedVote) etaMap {
 	allEtas := make(map[ID]*etaResult)
 	for _, vote := range votes {
 		if _, ok := allEtas[vote.TxHash]; !ok {
 			allEtas[vote.TxHash] = &etaResult{}
 		}
 		if vote.Opinion {
-			allEtas[vote.TxHash].value++
+			allEtas[vote.TxHash].value += vote.weight
 		}
 		allEtas[vote.TxHash].count++
-
+		allEtas[vote.TxHash].weight += vote.weight
 	}
 	for tx := range allEtas {
-		allEtas[tx].value /= float64(allEtas[tx].count)
+		if allEtas[tx].weight > 0 {
+			allEtas[tx].value /= allEtas[tx].weight
+		}
 	}
 
 	return allEtas
 }
 
-func choose(list []string, k int) []string {
-	chosen := make([]string, k) // slice containing the list of randomly selected nodes
-	rand := rand.New(rand.NewSource(time.Now().UnixNano()))
-	for i := 0; i < k; i++ {
-		chosen[i] = list[rand.Intn(len(list))]
-	}
-	return chosen
-}
-
 // runif returns a random uniform threshold bewteen
 // a lower bound and an upper bound
 func runif(rand, thresholdL, thresholdU float64) float64 {
@@ -0,0 +1,161 @@
+package fpc
+
+import (
+	"sync"
+	"time"
+)
+
+// gossipCounter accumulates the inbound gossiped opinions for a single tx,
+// so they can be fused with the directly queried etas at the end of a round.
+type gossipCounter struct {
+	likes int
+	total int
+}
+
+// gossipState holds the auxiliary, gossip-derived opinion counters and the
+// dedup cache used to drop gossip messages that were already folded in.
+type gossipState struct {
+	mu       sync.Mutex
+	counters map[ID]*gossipCounter
+	// seen tracks, per tx and sender, the most recent local round number a
+	// gossip message from that sender was folded in for. Unlike a permanent
+	// (peer, tx) lockout, this only suppresses duplicate deliveries within
+	// the same round, so later rounds keep receiving that peer's updates.
+	seen map[ID]map[string]uint64
+}
+
+// newGossipState returns a new, empty gossipState.
+func newGossipState() *gossipState {
+	return &gossipState{
+		counters: make(map[ID]*gossipCounter),
+		seen:     make(map[ID]map[string]uint64),
+	}
+}
+
+// receive folds the opinions gossiped by from into the counters, dropping any
+// (from, tx) pair already processed for the given round.
+func (g *gossipState) receive(from string, round uint64, txs []TxOpinion) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, vote := range txs {
+		seenAt, ok := g.seen[vote.TxHash]
+		if !ok {
+			seenAt = make(map[string]uint64)
+			g.seen[vote.TxHash] = seenAt
+		}
+		if lastRound, ok := seenAt[from]; ok && lastRound >= round {
+			continue
+		}
+		seenAt[from] = round
+
+		counter, ok := g.counters[vote.TxHash]
+		if !ok {
+			counter = &gossipCounter{}
+			g.counters[vote.TxHash] = counter
+		}
+		if vote.Opinion {
+			counter.likes++
+		}
+		counter.total++
+	}
+}
+
+// forget drops every gossip counter and dedup entry held for tx, called once
+// tx finalizes so the dedup cache doesn't grow unbounded over the process's
+// lifetime.
+func (g *gossipState) forget(tx ID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.counters, tx)
+	delete(g.seen, tx)
+}
+
+// fuse combines the directly queried etas with the gossip-derived counters
+// as a weighted average, alpha being the weight given to the gossip opinion.
+// Txs with no gossiped votes are returned unchanged. The gossip counters are
+// reset for every tx consumed here, so stale rounds don't linger.
+func (g *gossipState) fuse(etas etaMap, alpha float64) etaMap {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for tx, eta := range etas {
+		counter, ok := g.counters[tx]
+		if !ok || counter.total == 0 {
+			continue
+		}
+		gossipValue := float64(counter.likes) / float64(counter.total)
+		eta.value = (1-alpha)*eta.value + alpha*gossipValue
+		delete(g.counters, tx)
+	}
+	return etas
+}
+
+// ReceiveGossip folds an inbound batch of gossiped opinions from a peer into
+// the auxiliary gossip counters, to be fused into the next round() call. The
+// local round index is used to dedup repeated deliveries within the same
+// round without permanently locking out that peer's later updates.
+func (fpc *Instance) ReceiveGossip(from string, txs []TxOpinion) {
+	fpc.stateMu.Lock()
+	var round uint64
+	if fpc.state.tick != nil {
+		round = fpc.state.tick.index
+	}
+	fpc.stateMu.Unlock()
+
+	fpc.gossip.receive(from, round, txs)
+}
+
+// GossipTick periodically pushes this node's current interim opinion of all
+// active txs to up to GossipFanout randomly selected known peers. It runs
+// until the given channel is closed and is meant to be started in its own
+// goroutine alongside Tick.
+func (fpc *Instance) GossipTick(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			fpc.gossipRound()
+		case <-done:
+			return
+		}
+	}
+}
+
+// gossipRound pushes the current interim opinion of every active tx to a
+// bounded fanout of mana-weighted, randomly selected peers.
+func (fpc *Instance) gossipRound() {
+	if fpc.gossipOpinion == nil {
+		return
+	}
+
+	knownPeers := fpc.getKnownPeers()
+	if len(knownPeers) == 0 {
+		// nothing to gossip to yet (freshly bootstrapped or partitioned node)
+		return
+	}
+
+	fpc.stateMu.Lock()
+	activeTxs := fpc.state.getActiveTxs()
+	fpc.stateMu.Unlock()
+	if len(activeTxs) == 0 {
+		return
+	}
+
+	opinions := fpc.GetInterimOpinion(activeTxs...)
+	txs := make([]TxOpinion, len(activeTxs))
+	for i, tx := range activeTxs {
+		txs[i] = TxOpinion{TxHash: tx, Opinion: opinions[i]}
+	}
+
+	peers, _ := fpc.chooseWeighted(knownPeers, fpc.getPeerWeights(), fpc.state.parameters.GossipFanout)
+	if len(peers) == 0 {
+		return
+	}
+	if err := fpc.gossipOpinion(txs, peers); err != nil {
+		return
+	}
+}
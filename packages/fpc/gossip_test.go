@@ -0,0 +1,84 @@
+package fpc
+
+import "testing"
+
+func TestGossipStateReceiveFuseWeightedAverage(t *testing.T) {
+	g := newGossipState()
+	g.receive("peer1", 1, []TxOpinion{{TxHash: "tx1", Opinion: true}})
+	g.receive("peer2", 1, []TxOpinion{{TxHash: "tx1", Opinion: false}})
+
+	etas := etaMap{"tx1": &etaResult{value: 1}}
+	fused := g.fuse(etas, 0.5)
+
+	eta, ok := fused["tx1"]
+	if !ok {
+		t.Fatalf("expected an eta for tx1")
+	}
+	// queried eta is 1 (all like), gossip is 1 like out of 2 (0.5); fused at
+	// alpha=0.5 should land halfway between them
+	want := 0.75
+	if eta.value != want {
+		t.Fatalf("expected fused eta %v, got %v", want, eta.value)
+	}
+}
+
+func TestGossipStateFuseLeavesUngossipedTxUnchanged(t *testing.T) {
+	g := newGossipState()
+
+	etas := etaMap{"tx1": &etaResult{value: 0.3}}
+	fused := g.fuse(etas, 0.5)
+
+	if fused["tx1"].value != 0.3 {
+		t.Fatalf("expected eta for a tx with no gossip votes to stay 0.3, got %v", fused["tx1"].value)
+	}
+}
+
+func TestGossipStateFuseConsumesCounters(t *testing.T) {
+	g := newGossipState()
+	g.receive("peer1", 1, []TxOpinion{{TxHash: "tx1", Opinion: true}})
+
+	g.fuse(etaMap{"tx1": &etaResult{value: 1}}, 0.5)
+
+	// a second fuse of the same tx should see no gossip left to fold in
+	second := g.fuse(etaMap{"tx1": &etaResult{value: 1}}, 0.5)
+	if second["tx1"].value != 1 {
+		t.Fatalf("expected counters to be consumed by the first fuse, got %v", second["tx1"].value)
+	}
+}
+
+func TestGossipStateReceiveDedupsWithinRound(t *testing.T) {
+	g := newGossipState()
+	g.receive("peer1", 1, []TxOpinion{{TxHash: "tx1", Opinion: true}})
+	// same peer, same round: should be dropped rather than double-counted
+	g.receive("peer1", 1, []TxOpinion{{TxHash: "tx1", Opinion: true}})
+
+	counter := g.counters["tx1"]
+	if counter.total != 1 {
+		t.Fatalf("expected duplicate delivery within the same round to be dropped, total=%d", counter.total)
+	}
+}
+
+func TestGossipStateReceiveAllowsNextRoundFromSamePeer(t *testing.T) {
+	g := newGossipState()
+	g.receive("peer1", 1, []TxOpinion{{TxHash: "tx1", Opinion: true}})
+	g.receive("peer1", 2, []TxOpinion{{TxHash: "tx1", Opinion: true}})
+
+	counter := g.counters["tx1"]
+	if counter.total != 2 {
+		t.Fatalf("expected a later round's delivery from the same peer to be folded in, total=%d", counter.total)
+	}
+}
+
+func TestGossipStateForgetPurgesCountersAndDedup(t *testing.T) {
+	g := newGossipState()
+	g.receive("peer1", 1, []TxOpinion{{TxHash: "tx1", Opinion: true}})
+
+	g.forget("tx1")
+
+	if _, ok := g.counters["tx1"]; ok {
+		t.Fatalf("expected forget to remove the counter for tx1")
+	}
+	if _, ok := g.seen["tx1"]; ok {
+		t.Fatalf("expected forget to remove the dedup entry for tx1")
+	}
+}
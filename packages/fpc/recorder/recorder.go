@@ -0,0 +1,113 @@
+// Package recorder provides fpc.OpinionRecorder implementations for
+// persisting and exporting a node's FPC round history.
+package recorder
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/iotaledger/goshimmer/packages/fpc"
+)
+
+// RingBuffer is an in-memory fpc.OpinionRecorder that retains the most
+// recently recorded rounds for post-mortem inspection.
+type RingBuffer struct {
+	mu      sync.Mutex
+	records []fpc.RoundRecord
+	next    int
+	full    bool
+}
+
+// NewRingBuffer returns a RingBuffer that retains the last size round
+// records. It rejects a non-positive size with an error, since a RingBuffer
+// that can't hold at least one record is never useful.
+func NewRingBuffer(size int) (*RingBuffer, error) {
+	if size <= 0 {
+		return nil, errors.New("recorder: NewRingBuffer size must be positive")
+	}
+	return &RingBuffer{records: make([]fpc.RoundRecord, size)}, nil
+}
+
+// RecordRound implements fpc.OpinionRecorder.
+func (r *RingBuffer) RecordRound(index uint64, random float64, activeTxs map[fpc.ID]fpc.EtaSnapshot, opinions map[fpc.ID]fpc.Opinions) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records[r.next] = fpc.RoundRecord{
+		Index:     index,
+		Random:    random,
+		ActiveTxs: activeTxs,
+		Opinions:  opinions,
+	}
+	r.next++
+	if r.next == len(r.records) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// Snapshot returns the retained records in chronological order.
+func (r *RingBuffer) Snapshot() []fpc.RoundRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		out := make([]fpc.RoundRecord, r.next)
+		copy(out, r.records[:r.next])
+		return out
+	}
+
+	out := make([]fpc.RoundRecord, len(r.records))
+	n := copy(out, r.records[r.next:])
+	copy(out[n:], r.records[:r.next])
+	return out
+}
+
+// JSONLWriter is an fpc.OpinionRecorder that appends every round record as a
+// single JSON line, suitable for mounting as a docker volume and diffing
+// across nodes in the integration tests.
+type JSONLWriter struct {
+	mu      sync.Mutex
+	file    *os.File
+	enc     *json.Encoder
+	lastErr error
+}
+
+// NewJSONLWriter opens path for appending, creating it if necessary, and
+// returns a JSONLWriter that writes every recorded round to it.
+func NewJSONLWriter(path string) (*JSONLWriter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLWriter{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+// RecordRound implements fpc.OpinionRecorder.
+func (w *JSONLWriter) RecordRound(index uint64, random float64, activeTxs map[fpc.ID]fpc.EtaSnapshot, opinions map[fpc.ID]fpc.Opinions) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.enc.Encode(fpc.RoundRecord{
+		Index:     index,
+		Random:    random,
+		ActiveTxs: activeTxs,
+		Opinions:  opinions,
+	}); err != nil {
+		w.lastErr = err
+	}
+}
+
+// Err returns the last error encountered while writing a record, if any.
+func (w *JSONLWriter) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastErr
+}
+
+// Close closes the underlying file.
+func (w *JSONLWriter) Close() error {
+	return w.file.Close()
+}
@@ -0,0 +1,67 @@
+package recorder
+
+import (
+	"testing"
+
+	"github.com/iotaledger/goshimmer/packages/fpc"
+)
+
+func TestNewRingBufferRejectsNonPositiveSize(t *testing.T) {
+	if _, err := NewRingBuffer(0); err == nil {
+		t.Fatalf("expected an error for size 0")
+	}
+	if _, err := NewRingBuffer(-1); err == nil {
+		t.Fatalf("expected an error for a negative size")
+	}
+}
+
+func TestRingBufferSnapshotBeforeWraparound(t *testing.T) {
+	r, err := NewRingBuffer(3)
+	if err != nil {
+		t.Fatalf("NewRingBuffer: %v", err)
+	}
+
+	r.RecordRound(1, 0.1, nil, nil)
+	r.RecordRound(2, 0.2, nil, nil)
+
+	snapshot := r.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(snapshot))
+	}
+	if snapshot[0].Index != 1 || snapshot[1].Index != 2 {
+		t.Fatalf("expected records in chronological order, got %v", indices(snapshot))
+	}
+}
+
+func TestRingBufferSnapshotAfterWraparound(t *testing.T) {
+	r, err := NewRingBuffer(3)
+	if err != nil {
+		t.Fatalf("NewRingBuffer: %v", err)
+	}
+
+	// record 5 rounds into a buffer of size 3: rounds 1-2 should be evicted,
+	// leaving 3, 4, 5 in chronological order
+	for i := uint64(1); i <= 5; i++ {
+		r.RecordRound(i, float64(i), nil, nil)
+	}
+
+	snapshot := r.Snapshot()
+	if len(snapshot) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(snapshot))
+	}
+	want := []uint64{3, 4, 5}
+	got := indices(snapshot)
+	for i, w := range want {
+		if got[i] != w {
+			t.Fatalf("expected indices %v in chronological order, got %v", want, got)
+		}
+	}
+}
+
+func indices(records []fpc.RoundRecord) []uint64 {
+	out := make([]uint64, len(records))
+	for i, r := range records {
+		out[i] = r.Index
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+package fpc
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math"
+	"sort"
+)
+
+// cryptoRandSeed returns a seed for a *rand.Rand sourced from crypto/rand, so
+// the weighted sampler isn't predictable from the node's uptime like the old
+// time.Now().UnixNano() seed was.
+func cryptoRandSeed() int64 {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		panic(err)
+	}
+	return int64(binary.BigEndian.Uint64(buf[:]))
+}
+
+// weightedKey is a candidate peer together with its Efraimidis-Spirakis
+// selection key.
+type weightedKey struct {
+	nodeID string
+	weight float64
+	key    float64
+}
+
+// chooseWeighted selects up to k peers from list without replacement, using
+// weighted random sampling (Efraimidis-Spirakis): for every candidate compute
+// key = u^(1/w) with u~Uniform(0,1], then take the top k by key. Peers with
+// non-positive weight are never selected. It returns the selected peers and
+// the sum of their weights.
+func (fpc *Instance) chooseWeighted(list []string, weights map[string]float64, k int) (chosen []string, totalWeight float64) {
+	candidates := make([]weightedKey, 0, len(list))
+
+	fpc.rngMu.Lock()
+	for _, nodeID := range list {
+		w := weights[nodeID]
+		if w <= 0 {
+			continue
+		}
+		u := fpc.rng.Float64()
+		for u == 0 {
+			u = fpc.rng.Float64()
+		}
+		candidates = append(candidates, weightedKey{nodeID, w, math.Pow(u, 1/w)})
+	}
+	fpc.rngMu.Unlock()
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].key > candidates[j].key })
+
+	if k > len(candidates) {
+		k = len(candidates)
+	}
+	chosen = make([]string, k)
+	for i := 0; i < k; i++ {
+		chosen[i] = candidates[i].nodeID
+		totalWeight += candidates[i].weight
+	}
+	return chosen, totalWeight
+}
@@ -0,0 +1,121 @@
+package fpc
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// newTestInstance returns a bare Instance with just enough state for
+// exercising chooseWeighted/calculateEtas, without depending on the
+// Parameters/context machinery a full New() call would need.
+func newTestInstance() *Instance {
+	return &Instance{rng: rand.New(rand.NewSource(1))}
+}
+
+func TestChooseWeightedRespectsK(t *testing.T) {
+	fpc := newTestInstance()
+	nodes := []string{"a", "b", "c", "d", "e"}
+	weights := map[string]float64{"a": 1, "b": 1, "c": 1, "d": 1, "e": 1}
+
+	chosen, totalWeight := fpc.chooseWeighted(nodes, weights, 3)
+	if len(chosen) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(chosen))
+	}
+	if totalWeight != 3 {
+		t.Fatalf("expected totalWeight 3, got %v", totalWeight)
+	}
+
+	seen := make(map[string]bool)
+	for _, n := range chosen {
+		if seen[n] {
+			t.Fatalf("node %s selected twice, sampling should be without replacement", n)
+		}
+		seen[n] = true
+	}
+}
+
+func TestChooseWeightedExcludesNonPositiveWeight(t *testing.T) {
+	fpc := newTestInstance()
+	nodes := []string{"a", "b", "c"}
+	weights := map[string]float64{"a": 1, "b": 0, "c": -1}
+
+	chosen, totalWeight := fpc.chooseWeighted(nodes, weights, 3)
+	if len(chosen) != 1 || chosen[0] != "a" {
+		t.Fatalf("expected only 'a' to be selected, got %v", chosen)
+	}
+	if totalWeight != 1 {
+		t.Fatalf("expected totalWeight 1, got %v", totalWeight)
+	}
+}
+
+func TestChooseWeightedFavorsHigherWeight(t *testing.T) {
+	fpc := newTestInstance()
+	nodes := []string{"heavy", "light"}
+	weights := map[string]float64{"heavy": 1000, "light": 0.001}
+
+	const trials = 200
+	heavyFirst := 0
+	for i := 0; i < trials; i++ {
+		chosen, _ := fpc.chooseWeighted(nodes, weights, 1)
+		if len(chosen) == 1 && chosen[0] == "heavy" {
+			heavyFirst++
+		}
+	}
+
+	if heavyFirst < trials*9/10 {
+		t.Fatalf("expected the much heavier peer to dominate selection, got %d/%d", heavyFirst, trials)
+	}
+}
+
+// TestChooseWeightedTotalWeightBelowQuorum exercises the weight-sum that
+// querySample compares against Parameters.MinTotalWeight to abort a round
+// early when the selected sample doesn't reach quorum.
+func TestChooseWeightedTotalWeightBelowQuorum(t *testing.T) {
+	fpc := newTestInstance()
+	nodes := []string{"a", "b"}
+	weights := map[string]float64{"a": 0.1, "b": 0.1}
+
+	const minTotalWeight = 1.0
+	_, totalWeight := fpc.chooseWeighted(nodes, weights, 2)
+	if totalWeight >= minTotalWeight {
+		t.Fatalf("expected totalWeight below quorum threshold, got %v", totalWeight)
+	}
+}
+
+func TestCalculateEtasWeightNormalized(t *testing.T) {
+	votes := []weightedVote{
+		{TxOpinion{TxHash: "tx1", Opinion: true}, 3},
+		{TxOpinion{TxHash: "tx1", Opinion: false}, 1},
+	}
+
+	etas := calculateEtas(votes)
+	eta, ok := etas["tx1"]
+	if !ok {
+		t.Fatalf("expected an eta for tx1")
+	}
+
+	// "true" carries weight 3 out of a total weight of 4
+	want := 3.0 / 4.0
+	if math.Abs(eta.value-want) > 1e-9 {
+		t.Fatalf("expected weight-normalized eta %v, got %v", want, eta.value)
+	}
+	if eta.count != 2 {
+		t.Fatalf("expected count 2, got %d", eta.count)
+	}
+}
+
+func TestCalculateEtasIgnoresZeroTotalWeight(t *testing.T) {
+	votes := []weightedVote{
+		{TxOpinion{TxHash: "tx1", Opinion: true}, 0},
+	}
+
+	etas := calculateEtas(votes)
+	eta, ok := etas["tx1"]
+	if !ok {
+		t.Fatalf("expected an eta for tx1")
+	}
+	if eta.value != 0 {
+		t.Fatalf("expected eta value to stay 0 rather than divide by zero weight, got %v", eta.value)
+	}
+}
@@ -0,0 +1,86 @@
+package chaos
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/goshimmer/tools/integration-tests/tester/framework"
+)
+
+// netemState tracks the netem parameters currently applied to a container, so
+// InjectLatency and InjectPacketLoss can be combined into a single
+// `tc qdisc replace` instead of each clobbering the other's root qdisc.
+type netemState struct {
+	hasDelay bool
+	delay    time.Duration
+	jitter   time.Duration
+	hasLoss  bool
+	lossPct  float64
+}
+
+var (
+	netemMu   sync.Mutex
+	netemByID = make(map[string]*netemState)
+)
+
+// InjectLatency adds delay (+/- jitter) to every packet leaving container,
+// composing with any packet loss already injected via InjectPacketLoss.
+func InjectLatency(container *framework.DockerContainer, delay, jitter time.Duration) error {
+	netemMu.Lock()
+	state := netemStateFor(container.ID())
+	state.hasDelay = true
+	state.delay = delay
+	state.jitter = jitter
+	args := state.qdiscArgs()
+	netemMu.Unlock()
+
+	return container.Exec(args...)
+}
+
+// InjectPacketLoss drops pct percent of the packets leaving container,
+// composing with any latency already injected via InjectLatency.
+func InjectPacketLoss(container *framework.DockerContainer, pct float64) error {
+	netemMu.Lock()
+	state := netemStateFor(container.ID())
+	state.hasLoss = true
+	state.lossPct = pct
+	args := state.qdiscArgs()
+	netemMu.Unlock()
+
+	return container.Exec(args...)
+}
+
+// ClearNetem removes any netem qdisc previously installed by InjectLatency or
+// InjectPacketLoss on container.
+func ClearNetem(container *framework.DockerContainer) error {
+	netemMu.Lock()
+	delete(netemByID, container.ID())
+	netemMu.Unlock()
+
+	return container.Exec("tc", "qdisc", "del", "dev", "eth0", "root", "netem")
+}
+
+// netemStateFor returns the netemState for containerID, creating it if
+// necessary. Callers must hold netemMu.
+func netemStateFor(containerID string) *netemState {
+	state, ok := netemByID[containerID]
+	if !ok {
+		state = &netemState{}
+		netemByID[containerID] = state
+	}
+	return state
+}
+
+// qdiscArgs builds the `tc qdisc replace ... netem ...` invocation for the
+// current combination of delay/jitter and packet loss.
+func (s *netemState) qdiscArgs() []string {
+	args := []string{"tc", "qdisc", "replace", "dev", "eth0", "root", "netem"}
+	if s.hasDelay {
+		args = append(args, "delay", fmt.Sprintf("%dms", s.delay.Milliseconds()), fmt.Sprintf("%dms", s.jitter.Milliseconds()))
+	}
+	if s.hasLoss {
+		args = append(args, "loss", fmt.Sprintf("%.2f%%", s.lossPct))
+	}
+	return args
+}
@@ -0,0 +1,77 @@
+// Package chaos orchestrates network partitions and latency/packet-loss
+// injection on top of the Docker network primitives in framework, so
+// integration tests can reproduce the double-spend / eclipse scenarios the
+// FPC paper analyses without hand-editing docker networks.
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+
+	"github.com/iotaledger/goshimmer/tools/integration-tests/tester/framework"
+)
+
+// Partition isolates a set of peers from the rest of a shared docker network
+// by moving them onto a fresh, partition-local network.
+type Partition struct {
+	client          *client.Client
+	sharedNetworkID string
+	peers           []*framework.DockerContainer
+	partitionNetID  string
+	healed          bool
+}
+
+// NewPartition isolates peers from sharedNetworkID by disconnecting them and
+// reattaching them on a freshly created, partition-local docker network.
+func NewPartition(c *client.Client, sharedNetworkID string, peers []*framework.DockerContainer) (*Partition, error) {
+	resp, err := c.NetworkCreate(context.Background(), fmt.Sprintf("partition-%d", rand.Int63()), types.NetworkCreate{})
+	if err != nil {
+		return nil, fmt.Errorf("could not create partition network: %w", err)
+	}
+
+	p := &Partition{
+		client:          c,
+		sharedNetworkID: sharedNetworkID,
+		peers:           peers,
+		partitionNetID:  resp.ID,
+	}
+
+	for _, peer := range peers {
+		if err := peer.DisconnectFromNetwork(sharedNetworkID); err != nil {
+			return nil, fmt.Errorf("could not disconnect %s from shared network: %w", peer.ID(), err)
+		}
+		if err := peer.ConnectToNetwork(p.partitionNetID); err != nil {
+			return nil, fmt.Errorf("could not connect %s to partition network: %w", peer.ID(), err)
+		}
+	}
+
+	return p, nil
+}
+
+// Heal restores the partitioned peers' connectivity to the shared network
+// and removes the partition-local network. It is a no-op if already healed.
+func (p *Partition) Heal() error {
+	if p.healed {
+		return nil
+	}
+
+	for _, peer := range p.peers {
+		if err := peer.DisconnectFromNetwork(p.partitionNetID); err != nil {
+			return fmt.Errorf("could not disconnect %s from partition network: %w", peer.ID(), err)
+		}
+		if err := peer.ConnectToNetwork(p.sharedNetworkID); err != nil {
+			return fmt.Errorf("could not reconnect %s to shared network: %w", peer.ID(), err)
+		}
+	}
+
+	if err := p.client.NetworkRemove(context.Background(), p.partitionNetID); err != nil {
+		return fmt.Errorf("could not remove partition network: %w", err)
+	}
+
+	p.healed = true
+	return nil
+}
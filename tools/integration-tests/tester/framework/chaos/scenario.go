@@ -0,0 +1,43 @@
+package chaos
+
+import "time"
+
+// Event is a single action scheduled as part of a Scenario's timeline.
+type Event struct {
+	// After is the delay, relative to the previous event, after which Do runs.
+	After time.Duration
+	// Do performs the event's action.
+	Do func() error
+}
+
+// Scenario schedules a sequence of partition/heal/latency events on a
+// timeline, so FPC liveness tests can reproduce network conditions like
+// eclipse attacks or flapping links deterministically.
+type Scenario struct {
+	events []Event
+}
+
+// NewScenario returns an empty Scenario.
+func NewScenario() *Scenario {
+	return &Scenario{}
+}
+
+// Then appends an event that runs after the given delay has elapsed since the
+// previous event (or since Run was called, for the first event).
+func (s *Scenario) Then(after time.Duration, do func() error) *Scenario {
+	s.events = append(s.events, Event{After: after, Do: do})
+	return s
+}
+
+// Run executes the scenario's events in order, blocking between them for
+// each event's configured delay. It stops and returns the first error
+// encountered.
+func (s *Scenario) Run() error {
+	for _, event := range s.events {
+		time.Sleep(event.After)
+		if err := event.Do(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
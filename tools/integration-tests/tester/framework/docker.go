@@ -78,6 +78,12 @@ func (d *DockerContainer) CreateGoShimmerPeer(config GoShimmerConfig) error {
 		ExposedPorts: nat.PortSet{
 			nat.Port("8080/tcp"): {},
 		},
+		Healthcheck: &container.HealthConfig{
+			Test:     strslice.StrSlice{"CMD", "curl", "-f", "http://localhost:8080/info"},
+			Interval: 2 * time.Second,
+			Timeout:  2 * time.Second,
+			Retries:  30,
+		},
 		Cmd: strslice.StrSlice{
 			"--skip-config=true",
 			"--logger.level=debug",
@@ -133,6 +139,16 @@ func (d *DockerContainer) CreateContainer(name string, containerConfig *containe
 	return nil
 }
 
+// ID returns the container's Docker ID.
+func (d *DockerContainer) ID() string {
+	return d.id
+}
+
+// Client returns the Docker client the container was created with.
+func (d *DockerContainer) Client() *client.Client {
+	return d.client
+}
+
 // ConnectToNetwork connects a container to an existent network in the docker host.
 func (d *DockerContainer) ConnectToNetwork(networkID string) error {
 	return d.client.NetworkConnect(context.Background(), networkID, d.id, nil)
@@ -160,6 +176,41 @@ func (d *DockerContainer) Stop() error {
 	return d.client.ContainerStop(context.Background(), d.id, &duration)
 }
 
+// Exec runs cmd inside the running container and waits for it to complete,
+// returning an error if it could not be started or exited non-zero.
+func (d *DockerContainer) Exec(cmd ...string) error {
+	ctx := context.Background()
+
+	resp, err := d.client.ContainerExecCreate(ctx, d.id, types.ExecConfig{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := d.client.ContainerExecStart(ctx, resp.ID, types.ExecStartCheck{}); err != nil {
+		return err
+	}
+
+	var inspect types.ContainerExecInspect
+	for {
+		inspect, err = d.client.ContainerExecInspect(ctx, resp.ID)
+		if err != nil {
+			return err
+		}
+		if !inspect.Running {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if inspect.ExitCode != 0 {
+		return fmt.Errorf("command %v exited with code %d", cmd, inspect.ExitCode)
+	}
+	return nil
+}
+
 // ExitStatus returns the exit status according to the container information.
 func (d *DockerContainer) ExitStatus() (int, error) {
 	resp, err := d.client.ContainerInspect(context.Background(), d.id)
@@ -0,0 +1,178 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/client"
+)
+
+// DockerEvents consumes the Docker daemon's event stream and translates it
+// into the typed container lifecycle callbacks registered per container ID,
+// so tests can react to crashes and health transitions instead of polling.
+type DockerEvents struct {
+	client *client.Client
+
+	mu                 sync.Mutex
+	exitHandlers       map[string]func(code int)
+	healthHandlers     map[string]func(status string)
+	oomHandlers        map[string]func()
+	disconnectHandlers map[string]func(networkID string)
+}
+
+// NewDockerEvents returns a new DockerEvents bound to the given Docker client.
+func NewDockerEvents(c *client.Client) *DockerEvents {
+	return &DockerEvents{
+		client:             c,
+		exitHandlers:       make(map[string]func(code int)),
+		healthHandlers:     make(map[string]func(status string)),
+		oomHandlers:        make(map[string]func()),
+		disconnectHandlers: make(map[string]func(networkID string)),
+	}
+}
+
+// StartEventLoop consumes the Docker daemon's event stream until ctx is
+// canceled or the daemon closes the stream, dispatching every event to the
+// handlers registered for its container.
+func (e *DockerEvents) StartEventLoop(ctx context.Context) error {
+	msgs, errs := e.client.Events(ctx, types.EventsOptions{})
+
+	for {
+		select {
+		case msg, ok := <-msgs:
+			if !ok {
+				return nil
+			}
+			e.dispatch(msg)
+		case err, ok := <-errs:
+			if !ok {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (e *DockerEvents) dispatch(msg events.Message) {
+	switch {
+	case msg.Type == "container" && msg.Action == "die":
+		if fn := e.lookupExit(msg.Actor.ID); fn != nil {
+			code, _ := strconv.Atoi(msg.Actor.Attributes["exitCode"])
+			fn(code)
+		}
+	case msg.Type == "container" && msg.Action == "oom":
+		if fn := e.lookupOOM(msg.Actor.ID); fn != nil {
+			fn()
+		}
+	case msg.Type == "container" && strings.HasPrefix(msg.Action, "health_status:"):
+		if fn := e.lookup(e.healthHandlers, msg.Actor.ID); fn != nil {
+			fn(strings.TrimSpace(strings.TrimPrefix(msg.Action, "health_status:")))
+		}
+	case msg.Type == "network" && msg.Action == "disconnect":
+		containerID := msg.Actor.Attributes["container"]
+		if fn := e.lookupDisconnect(containerID); fn != nil {
+			fn(msg.Actor.ID)
+		}
+	}
+}
+
+func (e *DockerEvents) lookup(handlers map[string]func(status string), containerID string) func(status string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return handlers[containerID]
+}
+
+func (e *DockerEvents) lookupExit(containerID string) func(code int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.exitHandlers[containerID]
+}
+
+func (e *DockerEvents) lookupOOM(containerID string) func() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.oomHandlers[containerID]
+}
+
+func (e *DockerEvents) lookupDisconnect(containerID string) func(networkID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.disconnectHandlers[containerID]
+}
+
+// onExit registers fn to be invoked with the exit code once the daemon
+// reports containerID as died.
+func (e *DockerEvents) onExit(containerID string, fn func(code int)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.exitHandlers[containerID] = fn
+}
+
+// onHealth registers fn to be invoked with the new health status every time
+// containerID's HEALTHCHECK transitions.
+func (e *DockerEvents) onHealth(containerID string, fn func(status string)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.healthHandlers[containerID] = fn
+}
+
+// offHealth removes the health handler registered for containerID, leaving
+// any exit/OOM/disconnect handlers registered for it untouched.
+func (e *DockerEvents) offHealth(containerID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.healthHandlers, containerID)
+}
+
+// Deregister removes every handler registered for containerID.
+func (e *DockerEvents) Deregister(containerID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.exitHandlers, containerID)
+	delete(e.healthHandlers, containerID)
+	delete(e.oomHandlers, containerID)
+	delete(e.disconnectHandlers, containerID)
+}
+
+// OnExit registers fn to be called with the container's exit code once the
+// Docker daemon reports it as died. e's event loop must already be running
+// for this registration to be delivered.
+func (d *DockerContainer) OnExit(e *DockerEvents, fn func(code int)) {
+	e.onExit(d.id, fn)
+}
+
+// OnHealth registers fn to be called with the new health status every time
+// the container's HEALTHCHECK transitions (e.g. "healthy", "unhealthy").
+func (d *DockerContainer) OnHealth(e *DockerEvents, fn func(status string)) {
+	e.onHealth(d.id, fn)
+}
+
+// WaitForHealthy blocks until the container's HEALTHCHECK reports "healthy",
+// or returns an error if timeout elapses first. It requires e's event loop
+// to already be running.
+func (d *DockerContainer) WaitForHealthy(e *DockerEvents, timeout time.Duration) error {
+	healthy := make(chan struct{})
+	d.OnHealth(e, func(status string) {
+		if status == "healthy" {
+			close(healthy)
+		}
+	})
+	defer e.offHealth(d.id)
+
+	select {
+	case <-healthy:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("container %s did not become healthy within %s", d.id, timeout)
+	}
+}